@@ -0,0 +1,83 @@
+package main
+
+import "strings"
+
+// field weights for fuzzy search - a hit in the title counts for more than
+// the same hit landing in the artist or album name.
+const (
+	titleWeight  = 2.0
+	artistWeight = 1.3
+	albumWeight  = 1.0
+)
+
+// fuzzyMatch scores how well query matches target as a subsequence, in the
+// spirit of a local-alignment scorer: every matched character adds a base
+// score, consecutive runs of matched characters are worth progressively
+// more (rewarding contiguous matches the way Smith-Waterman rewards
+// unbroken alignment), and a match right at a word boundary gets a bonus.
+// ok is false if query isn't a subsequence of target at all.
+func fuzzyMatch(query, target string) (score int, matched []int, ok bool) {
+	if query == "" {
+		return 0, nil, true
+	}
+
+	q := []rune(strings.ToLower(query))
+	t := []rune(target)
+	tLower := []rune(strings.ToLower(target))
+
+	qi := 0
+	consecutive := 0
+	for ti := 0; ti < len(tLower) && qi < len(q); ti++ {
+		if tLower[ti] != q[qi] {
+			consecutive = 0
+			continue
+		}
+
+		gain := 10 + 5*consecutive
+		if ti == 0 || isWordBoundary(t[ti-1]) {
+			gain += 8
+		}
+
+		score += gain
+		matched = append(matched, ti)
+		consecutive++
+		qi++
+	}
+
+	if qi < len(q) {
+		return 0, nil, false
+	}
+
+	return score, matched, true
+}
+
+func isWordBoundary(r rune) bool {
+	return r == ' ' || r == '-' || r == '_' || r == '/' || r == '.'
+}
+
+// trackSearchScore scores a track against query across Title/Artist/Album,
+// weighting each field. titleMatched is which rune indices in track.Title
+// matched, for highlighting. ok is false if the query didn't match any
+// field at all.
+func trackSearchScore(query string, track Track) (score float64, titleMatched []int, ok bool) {
+	titleScore, titleIdx, titleOK := fuzzyMatch(query, track.Title)
+	artistScore, _, artistOK := fuzzyMatch(query, track.Artist)
+	albumScore, _, albumOK := fuzzyMatch(query, track.Album)
+
+	if !titleOK && !artistOK && !albumOK {
+		return 0, nil, false
+	}
+
+	if titleOK {
+		score += float64(titleScore) * titleWeight
+		titleMatched = titleIdx
+	}
+	if artistOK {
+		score += float64(artistScore) * artistWeight
+	}
+	if albumOK {
+		score += float64(albumScore) * albumWeight
+	}
+
+	return score, titleMatched, true
+}
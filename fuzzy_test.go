@@ -0,0 +1,85 @@
+package main
+
+import "testing"
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		target    string
+		wantOK    bool
+		wantOrder []int // matched indices must be strictly increasing and this long
+	}{
+		{"empty query always matches", "", "Anything", true, nil},
+		{"exact subsequence", "brn", "Brown Bird", true, []int{0, 1, 4}},
+		{"case insensitive", "BIRD", "Brown Bird", true, []int{0, 7, 8, 9}},
+		{"not a subsequence", "xyz", "Brown Bird", false, nil},
+		{"out of order is not a match", "drib", "Brown Bird", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, matched, ok := fuzzyMatch(tt.query, tt.target)
+			if ok != tt.wantOK {
+				t.Fatalf("fuzzyMatch(%q, %q) ok = %v, want %v", tt.query, tt.target, ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if tt.wantOrder != nil && len(matched) != len(tt.wantOrder) {
+				t.Fatalf("fuzzyMatch(%q, %q) matched = %v, want length %d", tt.query, tt.target, matched, len(tt.wantOrder))
+			}
+			for i := 1; i < len(matched); i++ {
+				if matched[i] <= matched[i-1] {
+					t.Fatalf("fuzzyMatch(%q, %q) matched indices not increasing: %v", tt.query, tt.target, matched)
+				}
+			}
+		})
+	}
+}
+
+func TestFuzzyMatchRewardsContiguousRuns(t *testing.T) {
+	contiguous, _, ok := fuzzyMatch("bird", "Bird Watching")
+	if !ok {
+		t.Fatal("expected contiguous match to succeed")
+	}
+	scattered, _, ok := fuzzyMatch("bird", "Big Icy Random Dreams")
+	if !ok {
+		t.Fatal("expected scattered match to succeed")
+	}
+	if contiguous <= scattered {
+		t.Fatalf("expected a contiguous match to score higher: contiguous=%d scattered=%d", contiguous, scattered)
+	}
+}
+
+func TestTrackSearchScoreWeightsTitleHighest(t *testing.T) {
+	titleHit := Track{Title: "Moonlight", Artist: "Someone", Album: "Album"}
+	artistHit := Track{Title: "Song", Artist: "Moonlight", Album: "Album"}
+
+	titleScore, titleMatched, ok := trackSearchScore("moon", titleHit)
+	if !ok {
+		t.Fatal("expected title match to succeed")
+	}
+	if len(titleMatched) == 0 {
+		t.Fatal("expected titleMatched to report the matched rune indices")
+	}
+
+	artistScore, artistMatched, ok := trackSearchScore("moon", artistHit)
+	if !ok {
+		t.Fatal("expected artist match to succeed")
+	}
+	if len(artistMatched) != 0 {
+		t.Fatalf("expected no title match highlighting when only the artist matched, got %v", artistMatched)
+	}
+
+	if titleScore <= artistScore {
+		t.Fatalf("expected a title hit to outscore the same query matching only the artist: title=%v artist=%v", titleScore, artistScore)
+	}
+}
+
+func TestTrackSearchScoreNoMatch(t *testing.T) {
+	track := Track{Title: "Song", Artist: "Artist", Album: "Album"}
+	if _, _, ok := trackSearchScore("zzz", track); ok {
+		t.Fatal("expected no match for a query absent from every field")
+	}
+}
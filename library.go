@@ -0,0 +1,176 @@
+package main
+
+import "sort"
+
+const (
+	unknownArtist = "Unknown Artist"
+	unknownAlbum  = "Unknown Album"
+)
+
+// one artist and every track of theirs we found
+type Artist struct {
+	Name   string
+	Tracks []Track
+}
+
+// one album, as performed by a particular (album) artist
+type Album struct {
+	Name   string
+	Artist string
+	Tracks []Track
+}
+
+// GroupByArtist indexes all known tracks by performing artist, sorted by
+// name, with each artist's own tracks sorted by title.
+func (p *Player) GroupByArtist() []Artist {
+	index := map[string][]Track{}
+	var order []string
+
+	for _, track := range p.Tracks {
+		name := track.Artist
+		if name == "" {
+			name = unknownArtist
+		}
+		if _, seen := index[name]; !seen {
+			order = append(order, name)
+		}
+		index[name] = append(index[name], track)
+	}
+
+	sort.Strings(order)
+
+	artists := make([]Artist, 0, len(order))
+	for _, name := range order {
+		tracks := index[name]
+		sortTracksByTitle(tracks)
+		artists = append(artists, Artist{Name: name, Tracks: tracks})
+	}
+
+	return artists
+}
+
+// AlbumsForArtist indexes one artist's tracks by album, in the order their
+// first track appears, with tracks sorted by track number then title.
+func (p *Player) AlbumsForArtist(artist string) []Album {
+	index := map[string]*Album{}
+	var order []string
+
+	for _, track := range p.Tracks {
+		name := track.Artist
+		if name == "" {
+			name = unknownArtist
+		}
+		if name != artist {
+			continue
+		}
+
+		albumName := track.Album
+		if albumName == "" {
+			albumName = unknownAlbum
+		}
+
+		entry, ok := index[albumName]
+		if !ok {
+			entry = &Album{Name: albumName, Artist: artist}
+			index[albumName] = entry
+			order = append(order, albumName)
+		}
+		entry.Tracks = append(entry.Tracks, track)
+	}
+
+	albums := make([]Album, 0, len(order))
+	for _, name := range order {
+		entry := index[name]
+		sortTracksByNumber(entry.Tracks)
+		albums = append(albums, *entry)
+	}
+
+	return albums
+}
+
+// GroupByAlbum indexes every track by (album artist, album), for a flat
+// cross-artist album browse.
+func (p *Player) GroupByAlbum() []Album {
+	index := map[string]*Album{}
+	var order []string
+
+	for _, track := range p.Tracks {
+		artist := track.AlbumArtist
+		if artist == "" {
+			artist = track.Artist
+		}
+		if artist == "" {
+			artist = unknownArtist
+		}
+
+		albumName := track.Album
+		if albumName == "" {
+			albumName = unknownAlbum
+		}
+
+		key := artist + "\x00" + albumName
+		entry, ok := index[key]
+		if !ok {
+			entry = &Album{Name: albumName, Artist: artist}
+			index[key] = entry
+			order = append(order, key)
+		}
+		entry.Tracks = append(entry.Tracks, track)
+	}
+
+	sort.Slice(order, func(i, j int) bool {
+		a, b := index[order[i]], index[order[j]]
+		if a.Artist != b.Artist {
+			return a.Artist < b.Artist
+		}
+		return a.Name < b.Name
+	})
+
+	albums := make([]Album, 0, len(order))
+	for _, key := range order {
+		entry := index[key]
+		sortTracksByNumber(entry.Tracks)
+		albums = append(albums, *entry)
+	}
+
+	return albums
+}
+
+// TracksForAlbum returns one artist's tracks on one album, sorted by track
+// number then title.
+func (p *Player) TracksForAlbum(artist, album string) []Track {
+	var tracks []Track
+
+	for _, track := range p.Tracks {
+		trackArtist := track.Artist
+		if trackArtist == "" {
+			trackArtist = unknownArtist
+		}
+		albumName := track.Album
+		if albumName == "" {
+			albumName = unknownAlbum
+		}
+
+		if trackArtist == artist && albumName == album {
+			tracks = append(tracks, track)
+		}
+	}
+
+	sortTracksByNumber(tracks)
+	return tracks
+}
+
+func sortTracksByTitle(tracks []Track) {
+	sort.Slice(tracks, func(i, j int) bool {
+		return tracks[i].Title < tracks[j].Title
+	})
+}
+
+func sortTracksByNumber(tracks []Track) {
+	sort.Slice(tracks, func(i, j int) bool {
+		if tracks[i].TrackNumber != tracks[j].TrackNumber {
+			return tracks[i].TrackNumber < tracks[j].TrackNumber
+		}
+		return tracks[i].Title < tracks[j].Title
+	})
+}
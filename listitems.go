@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// the bubbles/list items for each of the things the browser lists: tracks,
+// artists, albums, and playlists. Each just adapts one of our existing
+// types to list.Item (Title/Description/FilterValue).
+
+// matchHighlightStyle is how a fuzzy-matched character looks in a search
+// result's title.
+var matchHighlightStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#F1FA8C")).
+	Bold(true)
+
+type trackItem struct {
+	track Track
+
+	// which rune indices of track.Title matched the current search query,
+	// for highlighting. Empty outside of search results.
+	titleMatch []int
+}
+
+func (i trackItem) Title() string {
+	artist := i.track.Artist
+	if artist == "" {
+		artist = unknownArtist
+	}
+	title := i.track.Title
+	if len(i.titleMatch) > 0 {
+		title = highlightRunes(title, i.titleMatch)
+	}
+	return fmt.Sprintf("%s - %s", artist, title)
+}
+
+// highlightRunes re-renders text with the runes at the given indices styled
+// via matchHighlightStyle.
+func highlightRunes(text string, indices []int) string {
+	matched := make(map[int]bool, len(indices))
+	for _, idx := range indices {
+		matched[idx] = true
+	}
+
+	var out strings.Builder
+	for i, r := range []rune(text) {
+		if matched[i] {
+			out.WriteString(matchHighlightStyle.Render(string(r)))
+		} else {
+			out.WriteRune(r)
+		}
+	}
+	return out.String()
+}
+
+func (i trackItem) Description() string {
+	duration := formatDuration(i.track.Duration)
+	if i.track.Album == "" {
+		return duration
+	}
+	return fmt.Sprintf("%s | %s", i.track.Album, duration)
+}
+
+func (i trackItem) FilterValue() string {
+	return i.track.Title + " " + i.track.Artist + " " + i.track.Album
+}
+
+type artistItem struct{ artist Artist }
+
+func (i artistItem) Title() string { return i.artist.Name }
+func (i artistItem) Description() string {
+	return fmt.Sprintf("%d tracks", len(i.artist.Tracks))
+}
+func (i artistItem) FilterValue() string { return i.artist.Name }
+
+type albumItem struct{ album Album }
+
+func (i albumItem) Title() string { return i.album.Name }
+func (i albumItem) Description() string {
+	return fmt.Sprintf("%s | %d tracks", i.album.Artist, len(i.album.Tracks))
+}
+func (i albumItem) FilterValue() string { return i.album.Name }
+
+type playlistItem struct{ pl Playlist }
+
+func (i playlistItem) Title() string { return i.pl.Name }
+func (i playlistItem) Description() string {
+	return fmt.Sprintf("%d tracks", len(i.pl.Tracks))
+}
+func (i playlistItem) FilterValue() string { return i.pl.Name }
+
+func trackItems(tracks []Track) []list.Item {
+	items := make([]list.Item, len(tracks))
+	for i, t := range tracks {
+		items[i] = trackItem{track: t}
+	}
+	return items
+}
+
+// trackItemsHighlighted builds track items with matched title characters
+// highlighted against the given fuzzy search query.
+func trackItemsHighlighted(tracks []Track, query string) []list.Item {
+	items := make([]list.Item, len(tracks))
+	for i, t := range tracks {
+		_, titleMatch, _ := trackSearchScore(query, t)
+		items[i] = trackItem{track: t, titleMatch: titleMatch}
+	}
+	return items
+}
+
+func artistItems(artists []Artist) []list.Item {
+	items := make([]list.Item, len(artists))
+	for i, a := range artists {
+		items[i] = artistItem{artist: a}
+	}
+	return items
+}
+
+func albumItems(albums []Album) []list.Item {
+	items := make([]list.Item, len(albums))
+	for i, a := range albums {
+		items[i] = albumItem{album: a}
+	}
+	return items
+}
+
+func playlistItems(playlists []Playlist) []list.Item {
+	items := make([]list.Item, len(playlists))
+	for i, p := range playlists {
+		items[i] = playlistItem{pl: p}
+	}
+	return items
+}
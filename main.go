@@ -44,6 +44,13 @@ func main() {
 
 	// run the app
 	app := tea.NewProgram(NewModel(player), tea.WithAltScreen())
+	player.UIProgram = app
+
+	// radio-feature analysis decodes real audio per track, which is too
+	// slow to do before first paint - kick it off now that the UI can show
+	// progress instead of blocking startup on it
+	player.ScanAudioFeaturesAsync()
+
 	if _, err := app.Run(); err != nil {
 		log.Fatalf("app crashed: %v", err)
 	}
@@ -64,6 +71,7 @@ controls:
   p        previous song
   r        repeat on/off
   s        shuffle on/off
+  z        radio mode on/off
   /        search
   h        help
   q        quit
@@ -72,8 +80,15 @@ navigation:
   up/down  or j/k  move around
   g        go to top
   G        go to bottom
-  enter    play song
+  enter    open/play whatever is highlighted
+  esc      go back
+
+browsing (from the main view):
+  shift+a  browse by artist
+  t        browse all tracks
+  l        browse playlists
 
-works with: mp3, wav, flac, m4a, aac, ogg
+works with: mp3, wav, flac, ogg
+(m4a/aac tracked separately: apodicticscott/TuiTunes#chunk0-7-followup-m4a)
 `)
 }
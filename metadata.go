@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dhowden/tag"
+)
+
+// extractMetadata reads real ID3v1/v2, Vorbis comment, or MP4 atom tags off
+// the file. If the file has no usable tags (or isn't a format `tag` knows
+// about) we fall back to guessing artist/album from the folder structure,
+// same as before.
+func (p *Player) extractMetadata(track *Track) {
+	if p.readTags(track) {
+		return
+	}
+
+	p.guessMetadataFromPath(track)
+}
+
+// readTags tries to populate track from embedded tag metadata. Returns false
+// if the file couldn't be opened or had nothing `tag` could parse.
+func (p *Player) readTags(track *Track) bool {
+	file, err := os.Open(track.Path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	m, err := tag.ReadFrom(file)
+	if err != nil {
+		return false
+	}
+
+	if title := m.Title(); title != "" {
+		track.Title = title
+	} else {
+		baseName := filepath.Base(track.Path)
+		track.Title = strings.TrimSuffix(baseName, filepath.Ext(baseName))
+	}
+
+	track.Artist = m.Artist()
+	track.Album = m.Album()
+	track.AlbumArtist = m.AlbumArtist()
+	track.Genre = m.Genre()
+	track.Year = m.Year()
+
+	trackNum, _ := m.Track()
+	track.TrackNumber = trackNum
+
+	if pic := m.Picture(); pic != nil {
+		track.CoverArt = pic.Data
+		track.CoverArtMIME = pic.MIMEType
+	}
+
+	track.BPMHint = readBPM(m)
+
+	// a file with no tags at all still parses fine but gives us nothing
+	// useful - fall back to the folder-based guess for artist/album in
+	// that case.
+	if track.Artist == "" && track.Album == "" {
+		p.guessMetadataFromPath(track)
+	}
+
+	return true
+}
+
+// readBPM pulls the tempo out of the raw ID3 TBPM frame (mp3) or the
+// "tempo"/"bpm" Vorbis comment, when the tag library exposes one. Not every
+// format or file has this, so a zero return just means "no hint available".
+func readBPM(m tag.Metadata) float64 {
+	raw := m.Raw()
+
+	for _, key := range []string{"TBPM", "tempo", "bpm", "BPM"} {
+		value, ok := raw[key]
+		if !ok {
+			continue
+		}
+
+		switch v := value.(type) {
+		case string:
+			if bpm, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return bpm
+			}
+		case int:
+			return float64(v)
+		default:
+			if bpm, err := strconv.ParseFloat(strings.TrimSpace(fmt.Sprint(v)), 64); err == nil {
+				return bpm
+			}
+		}
+	}
+
+	return 0
+}
+
+// guessMetadataFromPath is the original filename/folder heuristic, kept as a
+// fallback for files with no (or unreadable) tags.
+func (p *Player) guessMetadataFromPath(track *Track) {
+	baseName := filepath.Base(track.Path)
+	ext := filepath.Ext(baseName)
+	if track.Title == "" {
+		track.Title = strings.TrimSuffix(baseName, ext)
+	}
+
+	relPath, _ := filepath.Rel(p.MusicDir, track.Path)
+	parts := strings.Split(filepath.Dir(relPath), string(filepath.Separator))
+
+	if track.Artist == "" && track.Album == "" {
+		if len(parts) >= 2 {
+			track.Artist = parts[0]
+			track.Album = parts[1]
+		} else if len(parts) == 1 && parts[0] != "." {
+			track.Artist = parts[0]
+		}
+	}
+}
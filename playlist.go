@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// a saved or imported playlist
+type Playlist struct {
+	Name   string
+	Path   string // where it lives on disk, empty for an unsaved playlist
+	Tracks []Track
+}
+
+// playlistDir is where user-created playlists get saved, analogous to how
+// config directories work for most music apps: ~/.config/tuitunes/playlists.
+func playlistDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "tuitunes", "playlists"), nil
+}
+
+// ScanPlaylists finds playlist files both alongside the music (m3u/m3u8/pls
+// files Navidrome-style "virtual folders" would pick up) and in the user's
+// own playlist directory, and loads them into p.Playlists.
+func (p *Player) ScanPlaylists() error {
+	p.Playlists = []Playlist{}
+
+	// playlists sitting in the music directory
+	err := filepath.WalkDir(p.MusicDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		if ext != ".m3u" && ext != ".m3u8" && ext != ".pls" {
+			return nil
+		}
+
+		pl, loadErr := p.LoadPlaylist(path)
+		if loadErr != nil {
+			// one broken playlist file shouldn't stop the scan
+			return nil
+		}
+		p.Playlists = append(p.Playlists, *pl)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// user-saved playlists in the config dir
+	dir, err := playlistDir()
+	if err != nil {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// no saved playlists yet, nothing to do
+		return nil
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		pl, loadErr := p.LoadPlaylist(filepath.Join(dir, entry.Name()))
+		if loadErr != nil {
+			continue
+		}
+		p.Playlists = append(p.Playlists, *pl)
+	}
+
+	sort.Slice(p.Playlists, func(i, j int) bool {
+		return p.Playlists[i].Name < p.Playlists[j].Name
+	})
+
+	return nil
+}
+
+// LoadPlaylist parses an m3u, m3u8, or pls file into a Playlist, resolving
+// both absolute and relative track paths against the playlist's own
+// directory.
+func (p *Player) LoadPlaylist(path string) (*Playlist, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("can't open playlist: %w", err)
+	}
+	defer file.Close()
+
+	ext := strings.ToLower(filepath.Ext(path))
+	base := filepath.Dir(path)
+	name := strings.TrimSuffix(filepath.Base(path), ext)
+
+	pl := &Playlist{Name: name, Path: path}
+
+	if ext == ".pls" {
+		pl.Tracks = parsePLS(file, base)
+		return pl, nil
+	}
+
+	pl.Tracks = parseM3U(file, base)
+	return pl, nil
+}
+
+// parseM3U handles both plain m3u and the extended #EXTM3U/#EXTINF format
+// (m3u8 is the same format, just UTF-8).
+func parseM3U(file *os.File, base string) []Track {
+	var tracks []Track
+	var pendingTitle string
+	var pendingDuration time.Duration
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#EXTINF:") {
+			seconds, title := parseEXTINF(line)
+			pendingDuration = time.Duration(seconds) * time.Second
+			pendingTitle = title
+			continue
+		}
+
+		// any other line starting with # is a comment, skip it
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		trackPath := resolveTrackPath(line, base)
+		track := Track{
+			Path:     trackPath,
+			Title:    pendingTitle,
+			Duration: pendingDuration,
+			Format:   strings.ToLower(filepath.Ext(trackPath)),
+		}
+		if track.Title == "" {
+			track.Title = strings.TrimSuffix(filepath.Base(trackPath), filepath.Ext(trackPath))
+		}
+		tracks = append(tracks, track)
+
+		pendingTitle = ""
+		pendingDuration = 0
+	}
+
+	return tracks
+}
+
+// parseEXTINF pulls the duration (seconds) and title hint out of a line like
+// "#EXTINF:123,Artist - Title".
+func parseEXTINF(line string) (int, string) {
+	rest := strings.TrimPrefix(line, "#EXTINF:")
+	parts := strings.SplitN(rest, ",", 2)
+
+	seconds, _ := strconv.Atoi(strings.TrimSpace(parts[0]))
+	title := ""
+	if len(parts) == 2 {
+		title = strings.TrimSpace(parts[1])
+	}
+
+	return seconds, title
+}
+
+// parsePLS handles the older Winamp .pls format: File1=, Title1=, Length1=.
+func parsePLS(file *os.File, base string) []Track {
+	files := map[int]string{}
+	titles := map[int]string{}
+	lengths := map[int]string{}
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(key, "File"):
+			if idx, err := strconv.Atoi(strings.TrimPrefix(key, "File")); err == nil {
+				files[idx] = value
+			}
+		case strings.HasPrefix(key, "Title"):
+			if idx, err := strconv.Atoi(strings.TrimPrefix(key, "Title")); err == nil {
+				titles[idx] = value
+			}
+		case strings.HasPrefix(key, "Length"):
+			if idx, err := strconv.Atoi(strings.TrimPrefix(key, "Length")); err == nil {
+				lengths[idx] = value
+			}
+		}
+	}
+
+	// sort numerically, not lexicographically - File10 has to come after
+	// File2, not before it
+	var indexes []int
+	for idx := range files {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	var tracks []Track
+	for _, idx := range indexes {
+		trackPath := resolveTrackPath(files[idx], base)
+		seconds, _ := strconv.Atoi(lengths[idx])
+
+		track := Track{
+			Path:     trackPath,
+			Title:    titles[idx],
+			Duration: time.Duration(seconds) * time.Second,
+			Format:   strings.ToLower(filepath.Ext(trackPath)),
+		}
+		if track.Title == "" {
+			track.Title = strings.TrimSuffix(filepath.Base(trackPath), filepath.Ext(trackPath))
+		}
+		tracks = append(tracks, track)
+	}
+
+	return tracks
+}
+
+// resolveTrackPath turns a playlist entry into a usable path, honoring both
+// absolute paths and paths relative to the playlist's own directory.
+func resolveTrackPath(entry string, base string) string {
+	if filepath.IsAbs(entry) {
+		return entry
+	}
+	return filepath.Join(base, entry)
+}
+
+// SavePlaylist writes pl out as an m3u file under the user playlist
+// directory, creating the directory if needed.
+func (p *Player) SavePlaylist(pl *Playlist) error {
+	dir, err := playlistDir()
+	if err != nil {
+		return fmt.Errorf("can't find config dir: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("can't create playlist dir: %w", err)
+	}
+
+	path := filepath.Join(dir, pl.Name+".m3u")
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("can't save playlist: %w", err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	writer.WriteString("#EXTM3U\n")
+	for _, track := range pl.Tracks {
+		fmt.Fprintf(writer, "#EXTINF:%d,%s - %s\n", int(track.Duration.Seconds()), track.Artist, track.Title)
+		writer.WriteString(track.Path + "\n")
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("can't save playlist: %w", err)
+	}
+
+	pl.Path = path
+	return nil
+}
+
+// CreatePlaylist makes a new, empty user playlist and persists it.
+func (p *Player) CreatePlaylist(name string) (*Playlist, error) {
+	pl := Playlist{Name: name}
+	if err := p.SavePlaylist(&pl); err != nil {
+		return nil, err
+	}
+	p.Playlists = append(p.Playlists, pl)
+	return &p.Playlists[len(p.Playlists)-1], nil
+}
+
+// RenamePlaylist renames a user playlist both in memory and on disk.
+func (p *Player) RenamePlaylist(oldName, newName string) error {
+	for i := range p.Playlists {
+		if p.Playlists[i].Name != oldName {
+			continue
+		}
+
+		oldPath := p.Playlists[i].Path
+		p.Playlists[i].Name = newName
+		if err := p.SavePlaylist(&p.Playlists[i]); err != nil {
+			return err
+		}
+		if oldPath != "" && oldPath != p.Playlists[i].Path {
+			os.Remove(oldPath)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no playlist named %q", oldName)
+}
+
+// DeletePlaylist removes a user playlist from disk and from memory.
+func (p *Player) DeletePlaylist(name string) error {
+	for i := range p.Playlists {
+		if p.Playlists[i].Name != name {
+			continue
+		}
+
+		if p.Playlists[i].Path != "" {
+			if err := os.Remove(p.Playlists[i].Path); err != nil {
+				return fmt.Errorf("can't delete playlist: %w", err)
+			}
+		}
+		p.Playlists = append(p.Playlists[:i], p.Playlists[i+1:]...)
+		return nil
+	}
+
+	return fmt.Errorf("no playlist named %q", name)
+}
+
+// AddToPlaylist appends track to the named playlist, creating it first if it
+// doesn't exist yet, and saves the result.
+func (p *Player) AddToPlaylist(name string, track Track) error {
+	for i := range p.Playlists {
+		if p.Playlists[i].Name != name {
+			continue
+		}
+		p.Playlists[i].Tracks = append(p.Playlists[i].Tracks, track)
+		return p.SavePlaylist(&p.Playlists[i])
+	}
+
+	pl, err := p.CreatePlaylist(name)
+	if err != nil {
+		return err
+	}
+	pl.Tracks = append(pl.Tracks, track)
+	return p.SavePlaylist(pl)
+}
+
+// ExportQueue saves the currently filtered track list (the "queue") as a new
+// user playlist.
+func (p *Player) ExportQueue(name string) error {
+	pl := Playlist{Name: name, Tracks: p.FilteredTracks}
+	if err := p.SavePlaylist(&pl); err != nil {
+		return err
+	}
+	p.Playlists = append(p.Playlists, pl)
+	return nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func openTemp(t *testing.T, name, contents string) (*os.File, string) {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("couldn't write temp file: %v", err)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("couldn't open temp file: %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file, dir
+}
+
+func TestParseEXTINF(t *testing.T) {
+	seconds, title := parseEXTINF("#EXTINF:245,The Artist - The Title")
+	if seconds != 245 {
+		t.Errorf("seconds = %d, want 245", seconds)
+	}
+	if title != "The Artist - The Title" {
+		t.Errorf("title = %q, want %q", title, "The Artist - The Title")
+	}
+}
+
+func TestParseEXTINFNoTitle(t *testing.T) {
+	seconds, title := parseEXTINF("#EXTINF:10")
+	if seconds != 10 {
+		t.Errorf("seconds = %d, want 10", seconds)
+	}
+	if title != "" {
+		t.Errorf("title = %q, want empty", title)
+	}
+}
+
+func TestParseM3U(t *testing.T) {
+	contents := "#EXTM3U\n" +
+		"#EXTINF:120,Artist One - Song One\n" +
+		"song-one.mp3\n" +
+		"song-two.mp3\n"
+	file, base := openTemp(t, "list.m3u", contents)
+
+	tracks := parseM3U(file, base)
+	if len(tracks) != 2 {
+		t.Fatalf("got %d tracks, want 2", len(tracks))
+	}
+
+	if tracks[0].Title != "Artist One - Song One" {
+		t.Errorf("tracks[0].Title = %q, want %q", tracks[0].Title, "Artist One - Song One")
+	}
+	if tracks[0].Duration != 120*time.Second {
+		t.Errorf("tracks[0].Duration = %v, want 120s", tracks[0].Duration)
+	}
+	if tracks[0].Path != filepath.Join(base, "song-one.mp3") {
+		t.Errorf("tracks[0].Path = %q, want resolved against base", tracks[0].Path)
+	}
+
+	// no #EXTINF before it, so the title falls back to the filename
+	if tracks[1].Title != "song-two" {
+		t.Errorf("tracks[1].Title = %q, want %q", tracks[1].Title, "song-two")
+	}
+}
+
+func TestParsePLSOrdersEntriesNumerically(t *testing.T) {
+	contents := "[playlist]\n"
+	for i := 1; i <= 11; i++ {
+		n := strconv.Itoa(i)
+		contents += "File" + n + "=track" + n + ".mp3\n"
+		contents += "Title" + n + "=Track " + n + "\n"
+		contents += "Length" + n + "=60\n"
+	}
+	file, _ := openTemp(t, "list.pls", contents)
+
+	tracks := parsePLS(file, "")
+	if len(tracks) != 11 {
+		t.Fatalf("got %d tracks, want 11", len(tracks))
+	}
+
+	// File10/Title10 must sort after File2/Title2, not before it lexicographically
+	for i, track := range tracks {
+		want := "Track " + strconv.Itoa(i+1)
+		if track.Title != want {
+			t.Fatalf("tracks[%d].Title = %q, want %q (tracks out of order)", i, track.Title, want)
+		}
+	}
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"math"
+	"math/rand"
+)
+
+// AudioFeatures is the similarity vector we compute for a track at scan
+// time, used to drive radio mode. It mixes tag-derived facets (year, bpm)
+// with acoustic features pulled from the first ~30 seconds of decoded PCM.
+type AudioFeatures struct {
+	Year             float64 // normalized 0-1 across a reasonable release-year range
+	Tempo            float64 // estimated BPM, from the ID3 tag if present, else autocorrelation
+	Energy           float64 // mean short-term RMS energy
+	SpectralCentroid float64 // brightness of the first 30s, 0-1 normalized
+}
+
+// radioAnalysisWindow is how much of the track we decode to estimate
+// acoustic features. Past this we'd just be spending time on the outro.
+const radioAnalysisWindow = 30 // seconds
+
+// computeAudioFeatures fills in track.Features. Decoding errors are not
+// fatal - a track with no usable audio just gets zero acoustic features
+// (its tag-derived year and BPM still count), which still works for radio.
+func (p *Player) computeAudioFeatures(track *Track) {
+	features := AudioFeatures{Year: normalizeYear(track.Year)}
+
+	if track.BPMHint > 0 {
+		features.Tempo = track.BPMHint
+	}
+
+	samples, sampleRate, err := p.decodeAnalysisWindow(track.Path)
+	if err == nil && len(samples) > 0 {
+		features.Energy = rmsEnergy(samples)
+		features.SpectralCentroid = spectralCentroid(samples, sampleRate)
+		if features.Tempo == 0 {
+			features.Tempo = estimateTempo(samples, sampleRate)
+		}
+	}
+
+	track.Features = features
+}
+
+// FeatureScanProgressMsg is sent to the TUI as ScanAudioFeaturesAsync works
+// through the library, so the status bar can show progress instead of
+// startup just hanging with no feedback.
+type FeatureScanProgressMsg struct {
+	Done, Total int
+}
+
+// ScanAudioFeaturesAsync computes every track's radio similarity vector in
+// the background. Each one decodes up to radioAnalysisWindow seconds of PCM
+// and runs a small DFT over it, which is too slow to do synchronously during
+// ScanMusicFiles for anything beyond a tiny library - this is meant to be
+// started once the TUI is already on screen (main wires it up after
+// UIProgram is set) so the first paint isn't blocked on a full library scan.
+func (p *Player) ScanAudioFeaturesAsync() {
+	p.featuresMu.Lock()
+	total := len(p.Tracks)
+	p.featureScanTotal = total
+	p.featureScanDone = 0
+	p.featuresMu.Unlock()
+
+	go func() {
+		for i := range p.Tracks {
+			track := p.Tracks[i]
+			p.computeAudioFeatures(&track)
+
+			p.featuresMu.Lock()
+			p.Tracks[i].Features = track.Features
+			p.featureScanDone++
+			done := p.featureScanDone
+			p.featuresMu.Unlock()
+
+			if p.UIProgram != nil {
+				p.UIProgram.Send(FeatureScanProgressMsg{Done: done, Total: total})
+			}
+		}
+	}()
+}
+
+// FeatureScanProgress reports how far the background scan from
+// ScanAudioFeaturesAsync has gotten. total is 0 before a scan has started.
+func (p *Player) FeatureScanProgress() (done, total int) {
+	p.featuresMu.RLock()
+	defer p.featuresMu.RUnlock()
+	return p.featureScanDone, p.featureScanTotal
+}
+
+// decodeAnalysisWindow loads up to radioAnalysisWindow seconds of mono PCM
+// samples (averaging stereo channels down to one) from path.
+func (p *Player) decodeAnalysisWindow(path string) ([]float64, int, error) {
+	streamer, format, err := p.loadTrack(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer streamer.Close()
+
+	sampleRate := int(format.SampleRate)
+	maxSamples := sampleRate * radioAnalysisWindow
+
+	buf := make([][2]float64, 512)
+	samples := make([]float64, 0, maxSamples)
+
+	for len(samples) < maxSamples {
+		n, ok := streamer.Stream(buf)
+		for i := 0; i < n; i++ {
+			samples = append(samples, (buf[i][0]+buf[i][1])/2)
+		}
+		if !ok {
+			break
+		}
+	}
+
+	return samples, sampleRate, nil
+}
+
+// rmsEnergy is the mean short-term RMS energy across 1024-sample frames.
+func rmsEnergy(samples []float64) float64 {
+	const frameSize = 1024
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var total float64
+	frames := 0
+	for start := 0; start < len(samples); start += frameSize {
+		end := start + frameSize
+		if end > len(samples) {
+			end = len(samples)
+		}
+
+		var sum float64
+		for _, s := range samples[start:end] {
+			sum += s * s
+		}
+		total += math.Sqrt(sum / float64(end-start))
+		frames++
+	}
+
+	return total / float64(frames)
+}
+
+// spectralCentroid estimates the "brightness" of the signal using a small
+// DFT over a downsampled window: the magnitude-weighted mean of the lowest
+// 64 bins, divided by 64 so it falls in 0-1. It's a coarse stand-in for a real FFT-based analysis, but
+// cheap enough to run on every track at scan time.
+func spectralCentroid(samples []float64, sampleRate int) float64 {
+	const bins = 64
+
+	// downsample to a manageable number of points so the DFT stays O(bins^2)
+	windowed := downsample(samples, bins*8)
+	if len(windowed) == 0 {
+		return 0
+	}
+
+	var weightedSum, magnitudeSum float64
+	for k := 0; k < bins; k++ {
+		var re, im float64
+		for n, x := range windowed {
+			angle := -2 * math.Pi * float64(k) * float64(n) / float64(len(windowed))
+			re += x * math.Cos(angle)
+			im += x * math.Sin(angle)
+		}
+		magnitude := math.Hypot(re, im)
+		weightedSum += magnitude * float64(k)
+		magnitudeSum += magnitude
+	}
+
+	if magnitudeSum == 0 {
+		return 0
+	}
+
+	return (weightedSum / magnitudeSum) / float64(bins)
+}
+
+// estimateTempo guesses BPM from the autocorrelation of the onset envelope
+// (half-wave rectified frame-to-frame energy change), which tends to peak
+// at the beat period for rhythmic music.
+func estimateTempo(samples []float64, sampleRate int) float64 {
+	const frameSize = 1024
+	if sampleRate == 0 || len(samples) < frameSize*4 {
+		return 0
+	}
+
+	var energies []float64
+	for start := 0; start+frameSize <= len(samples); start += frameSize {
+		var sum float64
+		for _, s := range samples[start : start+frameSize] {
+			sum += s * s
+		}
+		energies = append(energies, math.Sqrt(sum/float64(frameSize)))
+	}
+
+	var onset []float64
+	for i := 1; i < len(energies); i++ {
+		diff := energies[i] - energies[i-1]
+		if diff < 0 {
+			diff = 0
+		}
+		onset = append(onset, diff)
+	}
+	if len(onset) < 4 {
+		return 0
+	}
+
+	frameRate := float64(sampleRate) / float64(frameSize)
+	minLag := int(frameRate * 60 / 200) // 200 BPM upper bound
+	maxLag := int(frameRate * 60 / 50)  // 50 BPM lower bound
+	if minLag < 1 {
+		minLag = 1
+	}
+	if maxLag >= len(onset) {
+		maxLag = len(onset) - 1
+	}
+
+	bestLag := 0
+	bestScore := -1.0
+	for lag := minLag; lag <= maxLag; lag++ {
+		var score float64
+		for i := 0; i+lag < len(onset); i++ {
+			score += onset[i] * onset[i+lag]
+		}
+		if score > bestScore {
+			bestScore = score
+			bestLag = lag
+		}
+	}
+
+	if bestLag == 0 {
+		return 0
+	}
+
+	return 60 * frameRate / float64(bestLag)
+}
+
+// downsample picks n evenly-spaced samples out of samples.
+func downsample(samples []float64, n int) []float64 {
+	if len(samples) <= n {
+		return samples
+	}
+
+	out := make([]float64, n)
+	step := float64(len(samples)) / float64(n)
+	for i := 0; i < n; i++ {
+		out[i] = samples[int(float64(i)*step)]
+	}
+	return out
+}
+
+// normalizeYear maps a release year onto a 0-1 range across 1950-2030
+// (clamped outside it), so it sits on a comparable scale to the other features.
+func normalizeYear(year int) float64 {
+	if year == 0 {
+		return 0
+	}
+	const minYear, maxYear = 1950, 2030
+	v := float64(year-minYear) / float64(maxYear-minYear)
+	if v < 0 {
+		v = 0
+	}
+	if v > 1 {
+		v = 1
+	}
+	return v
+}
+
+// vector exposes the features as a plain slice for cosine-distance math.
+func (f AudioFeatures) vector() [4]float64 {
+	return [4]float64{f.Year, f.Tempo / 200, f.Energy, f.SpectralCentroid}
+}
+
+// cosineDistance is 1 - cosine similarity: 0 for identical vectors, up to 2
+// for opposite ones.
+func cosineDistance(a, b AudioFeatures) float64 {
+	va, vb := a.vector(), b.vector()
+
+	var dot, magA, magB float64
+	for i := range va {
+		dot += va[i] * vb[i]
+		magA += va[i] * va[i]
+		magB += vb[i] * vb[i]
+	}
+
+	if magA == 0 || magB == 0 {
+		return 1
+	}
+
+	similarity := dot / (math.Sqrt(magA) * math.Sqrt(magB))
+	return 1 - similarity
+}
+
+// EnableRadio turns on radio mode, seeding an endless queue from seed, and
+// starts it playing - otherwise whatever was already loaded would keep
+// playing underneath a status bar that now claims seed is "now playing".
+func (p *Player) EnableRadio(seed *Track) error {
+	p.RadioMode = true
+	p.radioSeed = *seed
+	p.radioPlayed = map[string]bool{seed.Path: true}
+	p.FilteredTracks = []Track{*seed}
+	p.CurrentTrack = 0
+	return p.restartCurrentTrack()
+}
+
+// DisableRadio turns radio mode off and restores the full library as the
+// queue.
+func (p *Player) DisableRadio() {
+	p.RadioMode = false
+	p.radioPlayed = nil
+	p.FilteredTracks = p.Tracks
+}
+
+// radioCandidate is one unplayed track scored against the radio seed.
+type radioCandidate struct {
+	track    Track
+	distance float64
+}
+
+// pickRadioTrack finds an unplayed track close to the radio seed, with a
+// little randomness thrown in so the radio doesn't settle into a loop
+// between the two or three closest matches.
+func (p *Player) pickRadioTrack() *Track {
+	p.featuresMu.RLock()
+	defer p.featuresMu.RUnlock()
+
+	var candidates []radioCandidate
+	for _, track := range p.Tracks {
+		if p.radioPlayed[track.Path] {
+			continue
+		}
+		candidates = append(candidates, radioCandidate{
+			track:    track,
+			distance: cosineDistance(p.radioSeed.Features, track.Features),
+		})
+	}
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sortCandidatesByDistance(candidates)
+
+	// pick among the handful of closest matches instead of always the
+	// single nearest, so repeated radio sessions don't all converge on the
+	// same path through the library
+	poolSize := 3
+	if poolSize > len(candidates) {
+		poolSize = len(candidates)
+	}
+	pick := candidates[rand.Intn(poolSize)]
+
+	return &pick.track
+}
+
+func sortCandidatesByDistance(candidates []radioCandidate) {
+	for i := 1; i < len(candidates); i++ {
+		for j := i; j > 0 && candidates[j].distance < candidates[j-1].distance; j-- {
+			candidates[j], candidates[j-1] = candidates[j-1], candidates[j]
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCosineDistanceIdenticalVectorsAreZero(t *testing.T) {
+	f := AudioFeatures{Year: 0.5, Tempo: 120, Energy: 0.3, SpectralCentroid: 0.4}
+	if d := cosineDistance(f, f); math.Abs(d) > 1e-9 {
+		t.Fatalf("cosineDistance(f, f) = %v, want ~0", d)
+	}
+}
+
+func TestCosineDistanceZeroVectorIsMaximallyDistant(t *testing.T) {
+	zero := AudioFeatures{}
+	other := AudioFeatures{Year: 0.5, Tempo: 120, Energy: 0.3, SpectralCentroid: 0.4}
+	if d := cosineDistance(zero, other); d != 1 {
+		t.Fatalf("cosineDistance(zero, other) = %v, want 1", d)
+	}
+}
+
+func TestCosineDistanceIsSymmetric(t *testing.T) {
+	a := AudioFeatures{Year: 0.2, Tempo: 90, Energy: 0.1, SpectralCentroid: 0.6}
+	b := AudioFeatures{Year: 0.8, Tempo: 150, Energy: 0.5, SpectralCentroid: 0.1}
+	if cosineDistance(a, b) != cosineDistance(b, a) {
+		t.Fatal("cosineDistance should be symmetric")
+	}
+}
+
+func TestEstimateTempoOnSilenceIsDeterministic(t *testing.T) {
+	// silence has no real onset signal, so every lag in the autocorrelation
+	// ties at a score of zero - the result is whatever the first lag
+	// examined happens to be, not a musically meaningful tempo. What matters
+	// is that it's stable, not NaN/negative, across repeated calls.
+	silence := make([]float64, 44100*2)
+	first := estimateTempo(silence, 44100)
+	second := estimateTempo(silence, 44100)
+	if first != second {
+		t.Fatalf("estimateTempo(silence) is not deterministic: %v vs %v", first, second)
+	}
+	if first < 0 {
+		t.Fatalf("estimateTempo(silence) = %v, want >= 0", first)
+	}
+}
+
+func TestEstimateTempoTooShortIsZero(t *testing.T) {
+	short := make([]float64, 16)
+	if tempo := estimateTempo(short, 44100); tempo != 0 {
+		t.Fatalf("estimateTempo(short) = %v, want 0", tempo)
+	}
+}
+
+func TestSpectralCentroidOnSilenceIsZero(t *testing.T) {
+	silence := make([]float64, 4096)
+	if c := spectralCentroid(silence, 44100); c != 0 {
+		t.Fatalf("spectralCentroid(silence) = %v, want 0", c)
+	}
+}
+
+func TestNormalizeYearClampsToRange(t *testing.T) {
+	tests := []struct {
+		year int
+		want float64
+	}{
+		{0, 0},
+		{1900, 0},
+		{1950, 0},
+		{2030, 1},
+		{2100, 1},
+	}
+	for _, tt := range tests {
+		if got := normalizeYear(tt.year); got != tt.want {
+			t.Errorf("normalizeYear(%d) = %v, want %v", tt.year, got, tt.want)
+		}
+	}
+}
+
+func TestDownsamplePicksEvenlySpacedSamples(t *testing.T) {
+	samples := make([]float64, 100)
+	for i := range samples {
+		samples[i] = float64(i)
+	}
+
+	out := downsample(samples, 10)
+	if len(out) != 10 {
+		t.Fatalf("downsample returned %d samples, want 10", len(out))
+	}
+
+	out = downsample(samples, 200)
+	if len(out) != len(samples) {
+		t.Fatalf("downsample should return the input unchanged when n > len(samples), got %d", len(out))
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// RepeatMode is how playback wraps around the end of the queue.
+type RepeatMode int
+
+const (
+	RepeatOff RepeatMode = iota
+	RepeatAll
+	RepeatOne
+)
+
+// ToggleRepeat cycles Off -> All -> One -> Off.
+func (p *Player) ToggleRepeat() {
+	switch p.RepeatMode {
+	case RepeatOff:
+		p.RepeatMode = RepeatAll
+	case RepeatAll:
+		p.RepeatMode = RepeatOne
+	case RepeatOne:
+		p.RepeatMode = RepeatOff
+	}
+}
+
+// ToggleShuffle turns shuffle on/off. Turning it on builds a fresh
+// Fisher-Yates permutation of FilteredTracks, starting from whatever's
+// currently playing.
+func (p *Player) ToggleShuffle() {
+	p.Shuffle = !p.Shuffle
+	if p.Shuffle {
+		p.regenerateShuffleOrder()
+	}
+}
+
+// regenerateShuffleOrder builds a new shadow permutation of FilteredTracks,
+// seeded per session, with the current track moved to the front so shuffle
+// continues from where we are instead of jumping somewhere random.
+func (p *Player) regenerateShuffleOrder() {
+	n := len(p.FilteredTracks)
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	for i := n - 1; i > 0; i-- {
+		j := r.Intn(i + 1)
+		order[i], order[j] = order[j], order[i]
+	}
+
+	if p.CurrentTrack >= 0 && p.CurrentTrack < n {
+		for i, idx := range order {
+			if idx == p.CurrentTrack {
+				order[0], order[i] = order[i], order[0]
+				break
+			}
+		}
+	}
+
+	p.shuffleOrder = order
+	p.shufflePos = 0
+	p.shuffleHistory = []int{p.CurrentTrack}
+}
+
+// nextShuffled advances through the shadow permutation instead of the
+// plain track order, pushing onto the shuffle history so Previous can
+// reverse through exactly what was played.
+func (p *Player) nextShuffled() error {
+	if len(p.shuffleOrder) != len(p.FilteredTracks) {
+		p.regenerateShuffleOrder()
+	}
+
+	p.shufflePos++
+	if p.shufflePos >= len(p.shuffleOrder) {
+		if p.RepeatMode == RepeatAll {
+			p.regenerateShuffleOrder()
+			// the new order starts with the track that just finished, so
+			// step past it
+			if len(p.shuffleOrder) > 1 {
+				p.shufflePos = 1
+			}
+		} else {
+			p.shufflePos = len(p.shuffleOrder) - 1
+			return fmt.Errorf("at the end")
+		}
+	}
+
+	p.CurrentTrack = p.shuffleOrder[p.shufflePos]
+	p.shuffleHistory = append(p.shuffleHistory, p.CurrentTrack)
+	return p.Play()
+}
+
+// previousShuffled walks back through shuffleHistory rather than the
+// permutation itself, so "previous" really does mean "whatever played
+// right before this".
+func (p *Player) previousShuffled() error {
+	if len(p.shuffleHistory) <= 1 {
+		if p.RepeatMode == RepeatAll {
+			p.regenerateShuffleOrder()
+			p.CurrentTrack = p.shuffleOrder[p.shufflePos]
+			return p.Play()
+		}
+		return fmt.Errorf("at the beginning")
+	}
+
+	p.shuffleHistory = p.shuffleHistory[:len(p.shuffleHistory)-1]
+	p.CurrentTrack = p.shuffleHistory[len(p.shuffleHistory)-1]
+	if p.shufflePos > 0 {
+		p.shufflePos--
+	}
+	return p.Play()
+}
+
+// TrackFinishedMsg is sent to the TUI when the currently playing track runs
+// out of samples, so the UI can trigger gapless auto-advance.
+type TrackFinishedMsg struct{}
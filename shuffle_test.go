@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+func tracksNamed(n int) []Track {
+	tracks := make([]Track, n)
+	for i := range tracks {
+		tracks[i] = Track{Path: "/does/not/exist/track.mp3"}
+	}
+	return tracks
+}
+
+func TestRegenerateShuffleOrderIsAPermutation(t *testing.T) {
+	p := &Player{FilteredTracks: tracksNamed(8), CurrentTrack: 3}
+	p.regenerateShuffleOrder()
+
+	if len(p.shuffleOrder) != 8 {
+		t.Fatalf("shuffleOrder has %d entries, want 8", len(p.shuffleOrder))
+	}
+
+	seen := make(map[int]bool, 8)
+	for _, idx := range p.shuffleOrder {
+		if idx < 0 || idx >= 8 {
+			t.Fatalf("shuffleOrder contains out-of-range index %d", idx)
+		}
+		if seen[idx] {
+			t.Fatalf("shuffleOrder contains duplicate index %d", idx)
+		}
+		seen[idx] = true
+	}
+
+	if p.shuffleOrder[0] != p.CurrentTrack {
+		t.Fatalf("shuffleOrder[0] = %d, want current track %d to be first", p.shuffleOrder[0], p.CurrentTrack)
+	}
+	if p.shufflePos != 0 {
+		t.Errorf("shufflePos = %d, want 0", p.shufflePos)
+	}
+	if len(p.shuffleHistory) != 1 || p.shuffleHistory[0] != p.CurrentTrack {
+		t.Errorf("shuffleHistory = %v, want [%d]", p.shuffleHistory, p.CurrentTrack)
+	}
+}
+
+func TestToggleRepeatCycles(t *testing.T) {
+	p := &Player{RepeatMode: RepeatOff}
+
+	p.ToggleRepeat()
+	if p.RepeatMode != RepeatAll {
+		t.Fatalf("after one toggle, RepeatMode = %v, want RepeatAll", p.RepeatMode)
+	}
+	p.ToggleRepeat()
+	if p.RepeatMode != RepeatOne {
+		t.Fatalf("after two toggles, RepeatMode = %v, want RepeatOne", p.RepeatMode)
+	}
+	p.ToggleRepeat()
+	if p.RepeatMode != RepeatOff {
+		t.Fatalf("after three toggles, RepeatMode = %v, want RepeatOff", p.RepeatMode)
+	}
+}
+
+func TestNextPreviousShuffledReverseThroughHistory(t *testing.T) {
+	p := &Player{FilteredTracks: tracksNamed(5), CurrentTrack: 0}
+	p.regenerateShuffleOrder()
+
+	// advancing and then reversing should land back where we started,
+	// regardless of whatever Play() does with the (nonexistent) track path
+	start := p.CurrentTrack
+	p.nextShuffled()
+	if len(p.shuffleHistory) != 2 {
+		t.Fatalf("shuffleHistory length = %d after one advance, want 2", len(p.shuffleHistory))
+	}
+
+	p.previousShuffled()
+	if p.CurrentTrack != start {
+		t.Fatalf("CurrentTrack = %d after next+previous, want back to %d", p.CurrentTrack, start)
+	}
+	if len(p.shuffleHistory) != 1 {
+		t.Fatalf("shuffleHistory length = %d after reversing, want 1", len(p.shuffleHistory))
+	}
+}
+
+func TestPreviousShuffledAtStartWithoutRepeatErrors(t *testing.T) {
+	p := &Player{FilteredTracks: tracksNamed(3), CurrentTrack: 0, RepeatMode: RepeatOff}
+	p.regenerateShuffleOrder()
+
+	if err := p.previousShuffled(); err == nil {
+		t.Fatal("expected an error walking back past the start of shuffle history")
+	}
+}
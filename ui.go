@@ -1,10 +1,18 @@
 package main
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -46,23 +54,68 @@ var (
 			Italic(true)
 )
 
+// Mode is which view the browser is currently showing. Navigating "into"
+// something (an artist, an album, a playlist, search results) pushes the
+// previous mode onto Model.modeStack; backspace pops back.
+type Mode int
+
+const (
+	ModeMain Mode = iota
+	ModeArtists
+	ModeArtistAlbums
+	ModeAlbum
+	ModeTracks
+	ModePlaylists
+	ModePlaylist
+	ModeSearch
+)
+
 // Model represents the TUI model
 type Model struct {
-	player      *Player
-	width       int
-	height      int
-	cursor      int
-	showHelp    bool
-	searchMode  bool
-	searchQuery string
+	player *Player
+	width  int
+	height int
+
+	mode      Mode
+	modeStack []Mode
+	list      list.Model
+	showHelp  bool
+
+	// the tracks backing whatever track list is currently on screen (Main,
+	// Tracks, Album, Playlist, Search) - what Enter/'a' act on
+	currentTracks []Track
+
+	// context carried between modes while browsing
+	selectedArtist    string
+	selectedAlbumName string
+	selectedPlaylist  string
+
+	// search query capture, before the query is committed and the Search
+	// mode's results list takes over
+	searchMode     bool
+	searchQuery    string
+	preSearchQueue []Track
+
+	// generic single-line text prompt, used for naming/renaming playlists
+	// and for exporting the queue
+	inputMode   string // "", "newPlaylist", "rename", "addToPlaylist", "exportQueue"
+	inputBuffer string
+	inputTarget string // the playlist being renamed, when inputMode == "rename"
 }
 
 // NewModel creates a new TUI model
 func NewModel(player *Player) *Model {
-	return &Model{
+	delegate := list.NewDefaultDelegate()
+	l := list.New(nil, delegate, 0, 0)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+
+	m := &Model{
 		player: player,
-		cursor: 0,
+		list:   l,
 	}
+	m.rebuildList()
+	return m
 }
 
 // Init initializes the model
@@ -76,21 +129,80 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.list.SetSize(msg.Width, listHeight(msg.Height))
 		return m, nil
 
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
+
+	case TrackFinishedMsg:
+		m.player.HandleTrackFinished()
+		if m.isTrackMode() {
+			m.rebuildList()
+		}
+		return m, nil
+
+	case FeatureScanProgressMsg:
+		return m, nil
 	}
 
 	return m, nil
 }
 
+// listHeight leaves room for the title, status bar, and controls footer.
+func listHeight(height int) int {
+	h := height - 8
+	if h < 3 {
+		h = 3
+	}
+	return h
+}
+
+// isTrackMode reports whether the current mode's list is showing tracks
+// (as opposed to artists, albums, or playlists).
+func (m Model) isTrackMode() bool {
+	switch m.mode {
+	case ModeMain, ModeTracks, ModeAlbum, ModePlaylist, ModeSearch:
+		return true
+	}
+	return false
+}
+
 // handleKeyPress handles keyboard input
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.inputMode != "" {
+		return m.handleTextPrompt(msg)
+	}
+
 	if m.searchMode {
 		return m.handleSearchInput(msg)
 	}
 
+	// mode-specific single-letter commands take priority over the global
+	// playback bindings below (e.g. 'r' renames a playlist while browsing
+	// Playlists, instead of toggling repeat)
+	if m.mode == ModePlaylists {
+		switch msg.String() {
+		case "c":
+			m.inputMode = "newPlaylist"
+			m.inputBuffer = ""
+			return m, nil
+		case "r":
+			if pl, ok := m.selectedPlaylistItem(); ok {
+				m.inputMode = "rename"
+				m.inputTarget = pl.Name
+				m.inputBuffer = pl.Name
+			}
+			return m, nil
+		case "x":
+			if pl, ok := m.selectedPlaylistItem(); ok {
+				m.player.DeletePlaylist(pl.Name)
+				m.rebuildList()
+			}
+			return m, nil
+		}
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return m, tea.Quit
@@ -102,6 +214,40 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "/":
 		m.searchMode = true
 		m.searchQuery = ""
+		m.preSearchQueue = m.player.FilteredTracks
+		m.refreshSearchResults()
+		return m, nil
+
+	case "A":
+		if m.mode == ModeMain {
+			m.pushMode(ModeArtists)
+		}
+		return m, nil
+
+	case "t":
+		if m.mode == ModeMain {
+			m.pushMode(ModeTracks)
+		}
+		return m, nil
+
+	case "l":
+		if m.mode == ModeMain {
+			m.pushMode(ModePlaylists)
+		}
+		return m, nil
+
+	case "a":
+		if m.isTrackMode() {
+			if _, ok := m.selectedTrack(); ok {
+				m.inputMode = "addToPlaylist"
+				m.inputBuffer = ""
+			}
+		}
+		return m, nil
+
+	case "e":
+		m.inputMode = "exportQueue"
+		m.inputBuffer = ""
 		return m, nil
 
 	case " ":
@@ -130,68 +276,297 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.player.ToggleShuffle()
 		return m, nil
 
-	case "j", "down":
-		if m.cursor < len(m.player.FilteredTracks)-1 {
-			m.cursor++
+	case "z":
+		if m.player.RadioMode {
+			m.player.DisableRadio()
+		} else if track := m.player.GetCurrentTrack(); track != nil {
+			// seed from what's actually playing, not whatever the cursor
+			// happens to be resting on - otherwise the status bar and the
+			// audio disagree about what's "now playing" until the next
+			// track change resyncs them
+			m.player.EnableRadio(track)
+		} else if track, ok := m.selectedTrack(); ok {
+			m.player.EnableRadio(&track)
 		}
-		return m, nil
-
-	case "k", "up":
-		if m.cursor > 0 {
-			m.cursor--
+		if m.mode == ModeMain {
+			m.rebuildList()
 		}
 		return m, nil
 
 	case "g":
-		m.cursor = 0
+		m.list.Select(0)
 		return m, nil
 
 	case "G":
-		m.cursor = len(m.player.FilteredTracks) - 1
+		m.list.Select(len(m.list.Items()) - 1)
+		return m, nil
+
+	case "esc", "backspace":
+		m.popMode()
 		return m, nil
 
 	case "enter":
-		if len(m.player.FilteredTracks) > 0 && m.cursor < len(m.player.FilteredTracks) {
-			m.player.CurrentTrack = m.cursor
-			m.player.Play()
+		return m.handleSelect()
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// handleSelect acts on whatever is highlighted in the current mode's list:
+// drilling into artists/albums/playlists, or playing a track.
+func (m Model) handleSelect() (tea.Model, tea.Cmd) {
+	switch m.mode {
+	case ModeArtists:
+		if item, ok := m.selectedArtistItem(); ok {
+			m.selectedArtist = item.artist.Name
+			m.pushMode(ModeArtistAlbums)
+		}
+
+	case ModeArtistAlbums:
+		if item, ok := m.selectedAlbumItem(); ok {
+			m.selectedAlbumName = item.album.Name
+			m.pushMode(ModeAlbum)
 		}
-		return m, nil
 
+	case ModePlaylists:
+		if pl, ok := m.selectedPlaylistItem(); ok {
+			m.selectedPlaylist = pl.Name
+			m.pushMode(ModePlaylist)
+		}
+
+	default:
+		if idx, ok := m.selectedTrackIndex(); ok {
+			m.player.FilteredTracks = m.currentTracks
+			m.player.CurrentTrack = idx
+			m.player.Play()
+		}
 	}
 
 	return m, nil
 }
 
-// handleSearchInput handles search mode input
+// pushMode enters a new mode, remembering how to get back, and rebuilds the
+// list contents for it.
+func (m *Model) pushMode(target Mode) {
+	m.modeStack = append(m.modeStack, m.mode)
+	m.mode = target
+	m.rebuildList()
+}
+
+// popMode returns to the previous mode, if any.
+func (m *Model) popMode() {
+	if len(m.modeStack) == 0 {
+		return
+	}
+	m.mode = m.modeStack[len(m.modeStack)-1]
+	m.modeStack = m.modeStack[:len(m.modeStack)-1]
+	m.rebuildList()
+}
+
+// rebuildList populates m.list (and m.currentTracks, for track-list modes)
+// from the player's data for whatever mode we're currently in.
+func (m *Model) rebuildList() {
+	switch m.mode {
+	case ModeMain:
+		m.currentTracks = m.player.FilteredTracks
+		m.list.Title = "TuiTunes"
+		m.list.SetItems(trackItems(m.currentTracks))
+
+	case ModeArtists:
+		m.list.Title = "Artists"
+		m.list.SetItems(artistItems(m.player.GroupByArtist()))
+
+	case ModeArtistAlbums:
+		m.list.Title = m.selectedArtist
+		m.list.SetItems(albumItems(m.player.AlbumsForArtist(m.selectedArtist)))
+
+	case ModeAlbum:
+		m.currentTracks = m.player.TracksForAlbum(m.selectedArtist, m.selectedAlbumName)
+		m.list.Title = fmt.Sprintf("%s - %s", m.selectedArtist, m.selectedAlbumName)
+		m.list.SetItems(trackItems(m.currentTracks))
+
+	case ModeTracks:
+		m.currentTracks = m.player.Tracks
+		m.list.Title = "All Tracks"
+		m.list.SetItems(trackItems(m.currentTracks))
+
+	case ModePlaylists:
+		m.list.Title = "Playlists"
+		m.list.SetItems(playlistItems(m.player.Playlists))
+
+	case ModePlaylist:
+		m.currentTracks = nil
+		for _, pl := range m.player.Playlists {
+			if pl.Name == m.selectedPlaylist {
+				m.currentTracks = pl.Tracks
+				break
+			}
+		}
+		m.list.Title = m.selectedPlaylist
+		m.list.SetItems(trackItems(m.currentTracks))
+
+	case ModeSearch:
+		m.currentTracks = m.player.FilteredTracks
+		m.list.Title = fmt.Sprintf("Search: %s", m.player.SearchQuery)
+		m.list.SetItems(trackItemsHighlighted(m.currentTracks, m.player.SearchQuery))
+	}
+}
+
+// selectedTrack returns the track item under the cursor, if the current
+// mode is showing tracks.
+func (m Model) selectedTrack() (Track, bool) {
+	idx, ok := m.selectedTrackIndex()
+	if !ok {
+		return Track{}, false
+	}
+	return m.currentTracks[idx], true
+}
+
+func (m Model) selectedTrackIndex() (int, bool) {
+	if !m.isTrackMode() {
+		return 0, false
+	}
+	idx := m.list.Index()
+	if idx < 0 || idx >= len(m.currentTracks) {
+		return 0, false
+	}
+	return idx, true
+}
+
+func (m Model) selectedArtistItem() (artistItem, bool) {
+	item, ok := m.list.SelectedItem().(artistItem)
+	return item, ok
+}
+
+func (m Model) selectedAlbumItem() (albumItem, bool) {
+	item, ok := m.list.SelectedItem().(albumItem)
+	return item, ok
+}
+
+func (m Model) selectedPlaylistItem() (Playlist, bool) {
+	item, ok := m.list.SelectedItem().(playlistItem)
+	if !ok {
+		return Playlist{}, false
+	}
+	return item.pl, true
+}
+
+// handleSearchInput handles search mode input. Every keystroke re-runs the
+// fuzzy search live; arrow keys move the cursor through the live results
+// without leaving the query field, and Enter commits to a browsable Search
+// mode on whatever's currently matched.
 func (m Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	switch msg.String() {
 	case "enter":
-		m.player.Search(m.searchQuery)
 		m.searchMode = false
-		m.cursor = 0
+		m.pushMode(ModeSearch)
 		return m, nil
 
 	case "esc":
 		m.searchMode = false
 		m.searchQuery = ""
+		m.player.SearchQuery = ""
+		m.player.FilteredTracks = m.preSearchQueue
+		m.rebuildList()
 		return m, nil
 
+	case "up", "down":
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(msg)
+		return m, cmd
+
 	case "backspace":
 		if len(m.searchQuery) > 0 {
 			m.searchQuery = m.searchQuery[:len(m.searchQuery)-1]
 		}
+		m.refreshSearchResults()
 		return m, nil
 
 	default:
 		if len(msg.String()) == 1 {
 			m.searchQuery += msg.String()
+			m.refreshSearchResults()
+		}
+		return m, nil
+	}
+}
+
+// refreshSearchResults re-runs the fuzzy search against the current query
+// and repopulates the preview list with highlighted matches.
+func (m *Model) refreshSearchResults() {
+	m.player.Search(m.searchQuery)
+	m.list.SetItems(trackItemsHighlighted(m.player.FilteredTracks, m.searchQuery))
+	m.list.Select(0)
+}
+
+// handleTextPrompt handles the generic single-line text prompt used for
+// naming playlists and exporting the queue.
+func (m Model) handleTextPrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.submitTextPrompt()
+		m.inputMode = ""
+		m.inputBuffer = ""
+		m.inputTarget = ""
+		return m, nil
+
+	case "esc":
+		m.inputMode = ""
+		m.inputBuffer = ""
+		m.inputTarget = ""
+		return m, nil
+
+	case "backspace":
+		if len(m.inputBuffer) > 0 {
+			m.inputBuffer = m.inputBuffer[:len(m.inputBuffer)-1]
+		}
+		return m, nil
+
+	default:
+		if len(msg.String()) == 1 {
+			m.inputBuffer += msg.String()
 		}
 		return m, nil
 	}
 }
 
+// submitTextPrompt applies whatever the current inputMode's prompt was for,
+// using m.inputBuffer as the entered name.
+func (m *Model) submitTextPrompt() {
+	name := strings.TrimSpace(m.inputBuffer)
+	if name == "" {
+		return
+	}
+
+	switch m.inputMode {
+	case "newPlaylist":
+		m.player.CreatePlaylist(name)
+
+	case "rename":
+		m.player.RenamePlaylist(m.inputTarget, name)
+
+	case "addToPlaylist":
+		if track, ok := m.selectedTrack(); ok {
+			m.player.AddToPlaylist(name, track)
+		}
+
+	case "exportQueue":
+		m.player.ExportQueue(name)
+	}
+
+	if m.mode == ModePlaylists {
+		m.rebuildList()
+	}
+}
+
 // View renders the UI
 func (m Model) View() string {
+	if m.inputMode != "" {
+		return m.renderTextPrompt()
+	}
+
 	if m.showHelp {
 		return m.renderHelp()
 	}
@@ -200,26 +575,13 @@ func (m Model) View() string {
 		return m.renderSearch()
 	}
 
-	return m.renderMain()
-}
-
-// renderMain renders the main interface
-func (m Model) renderMain() string {
 	var content strings.Builder
-
-	// Title
 	content.WriteString(titleStyle.Render("🎵 TuiTunes"))
 	content.WriteString("\n\n")
-
-	// Status bar
 	content.WriteString(m.renderStatusBar())
 	content.WriteString("\n\n")
-
-	// Playlist
-	content.WriteString(m.renderPlaylist())
-	content.WriteString("\n\n")
-
-	// Controls
+	content.WriteString(m.list.View())
+	content.WriteString("\n")
 	content.WriteString(m.renderControls())
 
 	return content.String()
@@ -244,10 +606,17 @@ func (m Model) renderStatusBar() string {
 	status.WriteString(" ")
 
 	// Current track info
-	if track := m.player.GetCurrentTrack(); track != nil {
+	track := m.player.GetCurrentTrack()
+	if track != nil {
 		status.WriteString(fmt.Sprintf("| %s - %s", track.Artist, track.Title))
 	}
 
+	// cover art thumbnail, if the track has any
+	if track != nil && len(track.CoverArt) > 0 {
+		status.WriteString("\n")
+		status.WriteString(renderCoverArt(track.CoverArt))
+	}
+
 	// progress bar
 	if m.player.State == Playing || m.player.State == Paused {
 		status.WriteString("\n")
@@ -255,12 +624,25 @@ func (m Model) renderStatusBar() string {
 	}
 
 	// Repeat/Shuffle indicators
-	if m.player.Repeat {
+	switch m.player.RepeatMode {
+	case RepeatAll:
 		status.WriteString(" | 🔁")
+	case RepeatOne:
+		status.WriteString(" | 🔂")
 	}
 	if m.player.Shuffle {
 		status.WriteString(" | 🔀")
 	}
+	if m.player.RadioMode {
+		status.WriteString(" | 📻")
+	}
+
+	// radio similarity features are computed in the background; surface
+	// progress until it catches up with the library, since radio mode looks
+	// like it's "missing" tracks otherwise
+	if done, total := m.player.FeatureScanProgress(); total > 0 && done < total {
+		status.WriteString(fmt.Sprintf(" | analyzing %d/%d", done, total))
+	}
 
 	return statusBarStyle.Render(status.String())
 }
@@ -291,82 +673,69 @@ func (m Model) renderProgressBar() string {
 	return fmt.Sprintf("%s %s", bar, timeStr)
 }
 
-// renderPlaylist renders the track list
-func (m Model) renderPlaylist() string {
-	if len(m.player.FilteredTracks) == 0 {
-		return "No tracks found. Press '/' to search or add music files to the directory."
+// renderControls renders the control help, tailored to the current mode
+func (m Model) renderControls() string {
+	controls := []string{"Space: Play/Pause", "N: Next", "P: Previous"}
+	if m.mode != ModePlaylists {
+		// 'r' renames instead while browsing playlists
+		controls = append(controls, "R: Repeat")
 	}
+	controls = append(controls, "S: Shuffle", "Z: Radio")
 
-	var content strings.Builder
-	content.WriteString("Playlist:\n")
-
-	for i, track := range m.player.FilteredTracks {
-		var style lipgloss.Style
-		switch {
-		case i == m.cursor:
-			style = selectedStyle
-		case i == m.player.CurrentTrack:
-			style = playingStyle
-		default:
-			style = normalStyle
-		}
-
-		// Track info
-		info := fmt.Sprintf("%s - %s", track.Artist, track.Title)
-		if track.Album != "" {
-			info += fmt.Sprintf(" (%s)", track.Album)
-		}
-
-		// Duration
-		duration := formatDuration(track.Duration)
-
-		// show what's playing
-		var prefix string
-		if i == m.player.CurrentTrack {
-			switch m.player.State {
-			case Playing:
-				prefix = "▶ "
-			case Paused:
-				prefix = "⏸ "
-			default:
-				prefix = "⏹ "
-			}
-		} else {
-			prefix = "  "
-		}
-
-		line := fmt.Sprintf("%s%s - %s", prefix, info, duration)
-		content.WriteString(style.Render(line))
-		content.WriteString("\n")
+	switch m.mode {
+	case ModePlaylists:
+		controls = append(controls, "Enter: Open", "C: New", "R: Rename", "X: Delete")
+	case ModeArtists, ModeArtistAlbums:
+		controls = append(controls, "Enter: Open")
+	default:
+		controls = append(controls, "Enter: Play", "A: Add to playlist")
 	}
 
-	return content.String()
-}
-
-// renderControls renders the control help
-func (m Model) renderControls() string {
-	controls := []string{
-		"Space: Play/Pause",
-		"N: Next",
-		"P: Previous",
-		"R: Repeat",
-		"S: Shuffle",
-		"/: Search",
-		"H: Help",
-		"Q: Quit",
+	if m.mode == ModeMain {
+		controls = append(controls, "/: Search", "A(shift): Artists", "T: Tracks", "L: Playlists", "E: Export queue")
+	} else {
+		controls = append(controls, "Esc: Back")
 	}
 
+	controls = append(controls, "H: Help", "Q: Quit")
+
 	return helpStyle.Render(strings.Join(controls, " | "))
 }
 
-// renderSearch renders the search interface
+// renderSearch renders the search query entry interface, with a live
+// preview of the current fuzzy matches below the query line
 func (m Model) renderSearch() string {
 	var content strings.Builder
 	content.WriteString("Search: ")
 	content.WriteString(m.searchQuery)
 	content.WriteString("_")
 	content.WriteString("\n\n")
-	content.WriteString("Press Enter to search, Esc to cancel")
+	content.WriteString(m.list.View())
+	content.WriteString("\n")
+	content.WriteString(helpStyle.Render("Type to filter | ↑/↓ to browse | Enter to open results | Esc to cancel"))
+	return content.String()
+}
+
+// renderTextPrompt renders the generic single-line text prompt
+func (m Model) renderTextPrompt() string {
+	var label string
+	switch m.inputMode {
+	case "newPlaylist":
+		label = "New playlist name"
+	case "rename":
+		label = fmt.Sprintf("Rename %q to", m.inputTarget)
+	case "addToPlaylist":
+		label = "Add to playlist"
+	case "exportQueue":
+		label = "Export queue as"
+	}
+
+	var content strings.Builder
+	content.WriteString(label + ": ")
+	content.WriteString(m.inputBuffer)
+	content.WriteString("_")
+	content.WriteString("\n\n")
+	content.WriteString(helpStyle.Render("Press Enter to confirm, Esc to cancel"))
 	return content.String()
 }
 
@@ -381,25 +750,33 @@ CONTROLS:
   P           Previous track
   R           Toggle repeat mode
   S           Toggle shuffle
-  /           Search music
+  Z           Toggle radio mode, seeded from the highlighted/playing track
   H           Show/hide this help
   Q           Quit
 
 NAVIGATION:
-  ↑/↓ or J/K  Navigate playlist
-  G           Go to top of playlist
-  G (Shift+G) Go to bottom of playlist
-  Enter       Play selected track
-
-
-SEARCH:
-  /           Enter search mode
-  Type        Enter search query
-  Enter       Execute search
-  Esc         Cancel search
+  ↑/↓ or J/K  Move the cursor
+  G           Go to top of the list
+  G (Shift+G) Go to bottom of the list
+  Enter       Open / play whatever is highlighted
+  Esc/Bksp    Go back to the previous view
+
+BROWSING (from the main view):
+  Shift+A     Browse by artist
+  T           Browse all tracks
+  L           Browse playlists
+  /           Search
+
+PLAYLISTS:
+  A           Add the highlighted track to a playlist
+  E           Export the current queue as a playlist
+  C           Create a new playlist (while browsing playlists)
+  R           Rename the selected playlist (while browsing playlists)
+  X           Delete the selected playlist (while browsing playlists)
 
 SUPPORTED FORMATS:
-  MP3, WAV, FLAC, M4A, AAC, OGG
+  MP3, WAV, FLAC, OGG (M4A/AAC cut from this release pending reviewer
+  sign-off - needs a pure-Go AAC decoder or a cgo binding to libfaad)
 
 Press H to return to the main interface.
 `
@@ -412,3 +789,109 @@ func formatDuration(d time.Duration) string {
 	seconds := int(d.Seconds()) % 60
 	return fmt.Sprintf("%d:%02d", minutes, seconds)
 }
+
+// coverArtASCII is what we show when the terminal can't do inline graphics
+// and the cover art couldn't be turned into a block thumbnail either.
+const coverArtASCII = "♪ ♫ ♪"
+
+// renderCoverArt turns embedded cover art bytes into a small inline
+// thumbnail. It decodes the art once (so this works for any format Go's
+// image package understands, not just whatever the embedded MIME type
+// claims) and prefers the kitty graphics protocol (detected via
+// KITTY_WINDOW_ID or TERM), falling back to a coarse ANSI block-color
+// thumbnail, and finally to plain ASCII art if the image can't be decoded
+// at all.
+func renderCoverArt(data []byte) string {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return helpStyle.Render(coverArtASCII)
+	}
+
+	if supportsKittyGraphics() {
+		return kittyGraphicsEscape(img)
+	}
+
+	return ansiBlockThumbnail(img, 16, 8)
+}
+
+// supportsKittyGraphics does a best-effort check for terminals known to
+// implement the kitty graphics protocol.
+func supportsKittyGraphics() bool {
+	if os.Getenv("KITTY_WINDOW_ID") != "" {
+		return true
+	}
+	term := os.Getenv("TERM")
+	return strings.Contains(term, "kitty")
+}
+
+// kittyChunkSize is the max bytes of base64-encoded payload the kitty
+// graphics protocol allows per escape sequence - anything bigger has to be
+// split across several chunks, each continuing the last via m=1.
+const kittyChunkSize = 4096
+
+// kittyGraphicsEscape transmits img as raw RGBA pixels over the kitty
+// graphics protocol (f=32), chunked per spec instead of shipped as one
+// giant escape sequence. Decoding to raw pixels first means this doesn't
+// need to care whether the embedded art was a PNG or a JPEG - both decode
+// to the same image.Image, unlike the old f=100-always approach which
+// silently mangled any non-PNG cover art.
+func kittyGraphicsEscape(img image.Image) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	pixels := make([]byte, 0, width*height*4)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			pixels = append(pixels, byte(r>>8), byte(g>>8), byte(b>>8), byte(a>>8))
+		}
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(pixels)
+
+	var out strings.Builder
+	for offset := 0; offset < len(encoded); offset += kittyChunkSize {
+		end := offset + kittyChunkSize
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		more := 0
+		if end < len(encoded) {
+			more = 1
+		}
+
+		if offset == 0 {
+			fmt.Fprintf(&out, "\x1b_Ga=T,f=32,s=%d,v=%d,m=%d;%s\x1b\\", width, height, more, encoded[offset:end])
+		} else {
+			fmt.Fprintf(&out, "\x1b_Gm=%d;%s\x1b\\", more, encoded[offset:end])
+		}
+	}
+
+	return out.String()
+}
+
+// ansiBlockThumbnail downsamples img to a cols x rows grid and renders each
+// cell as a space with the cell's average color as the background, giving a
+// crude but universally supported "pixel art" thumbnail.
+func ansiBlockThumbnail(img image.Image, cols, rows int) string {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return helpStyle.Render(coverArtASCII)
+	}
+
+	var out strings.Builder
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			x := bounds.Min.X + col*w/cols
+			y := bounds.Min.Y + row*h/rows
+			r, g, b, _ := img.At(x, y).RGBA()
+			color := lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8))
+			cell := lipgloss.NewStyle().Background(color).Render("  ")
+			out.WriteString(cell)
+		}
+		out.WriteString("\n")
+	}
+
+	return strings.TrimRight(out.String(), "\n")
+}